@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -16,21 +17,38 @@ import (
 var DefaultShutdownTimeout = time.Second * 60
 
 type GracefulServer struct {
-	server   *http.Server
-	listener net.Listener
-	log      *logrus.Entry
-
-	URL             string
-	ShutdownTimeout time.Duration
+	server        *http.Server
+	listener      net.Listener
+	log           *logrus.Entry
+	certReloader  *certReloader
+	signals       []os.Signal
+	ready         atomic.Bool
+	shutdownHooks []ShutdownHook
+	conns         *connTracker
+
+	URL                      string
+	ShutdownTimeout          time.Duration
+	PreShutdownDelay         time.Duration
+	MaxConcurrentConnections int
 }
 
-func NewGracefulServer(handler http.Handler, log *logrus.Entry) *GracefulServer {
-	return &GracefulServer{
+func NewGracefulServer(handler http.Handler, log *logrus.Entry, opts ...Option) *GracefulServer {
+	svr := &GracefulServer{
 		server:          &http.Server{Handler: handler},
 		log:             log,
 		listener:        nil,
 		ShutdownTimeout: DefaultShutdownTimeout,
+		conns:           newConnTracker(),
+	}
+	svr.ready.Store(true)
+
+	for _, opt := range opts {
+		opt(svr)
 	}
+
+	svr.server.ConnState = svr.conns.trackConnState
+
+	return svr
 }
 
 func (svr *GracefulServer) Bind(addr string) error {
@@ -39,18 +57,24 @@ func (svr *GracefulServer) Bind(addr string) error {
 		return err
 	}
 	svr.URL = "http://" + l.Addr().String()
-	svr.listener = l
+	svr.listener = newLimitListener(l, svr.MaxConcurrentConnections)
 	return nil
 }
 
+// Listen serves on the bound listener. If the server was constructed with
+// WithSignalHandler, it also shuts down on the configured signals. Callers
+// that want to drive shutdown themselves, e.g. with signal.NotifyContext
+// or in tests, should use RunContext instead.
 func (svr *GracefulServer) Listen() error {
-	go svr.listenForShutdownSignal()
+	if len(svr.signals) > 0 {
+		go svr.listenForShutdownSignal()
+	}
 	return svr.server.Serve(svr.listener)
 }
 
 func (svr *GracefulServer) listenForShutdownSignal() {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	signal.Notify(c, svr.signals...)
 	sig := <-c
 	svr.log.Infof("Triggering shutdown from signal %s", sig)
 
@@ -66,6 +90,32 @@ func (svr *GracefulServer) listenForShutdownSignal() {
 
 }
 
+// RunContext serves on the bound listener until ctx is canceled, at which
+// point the server is shut down the same way Close does it. It lets
+// callers integrate with their own signal.NotifyContext, run several
+// servers without duplicate signal handlers fighting each other, and
+// exercise shutdown in tests by canceling ctx instead of sending real OS
+// signals.
+func (svr *GracefulServer) RunContext(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- svr.server.Serve(svr.listener)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutErr := svr.Close()
+		if shutErr == context.DeadlineExceeded {
+			svr.log.WithError(shutErr).Warnf("Forcing a shutdown after waiting %s", svr.ShutdownTimeout.String())
+			shutErr = svr.server.Close()
+		}
+		<-errc
+		return shutErr
+	}
+}
+
 func (svr *GracefulServer) ListenAndServe(addr string) error {
 	if svr.listener != nil {
 		return errors.New("The listener has already started, don't call Bind first")
@@ -77,10 +127,40 @@ func (svr *GracefulServer) ListenAndServe(addr string) error {
 	return svr.Listen()
 }
 
+// Close flips Readiness to false, waits PreShutdownDelay so load balancers
+// and Kubernetes endpoint controllers notice before traffic actually
+// stops, runs any hooks registered with OnShutdown, and then shuts the
+// underlying server down within ShutdownTimeout.
 func (svr *GracefulServer) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), svr.ShutdownTimeout)
 	defer cancel()
 
+	return svr.shutdown(ctx)
+}
+
+// shutdown runs the same drain-then-stop sequence as Close, but against a
+// caller-supplied ctx instead of one derived from ShutdownTimeout. Group
+// uses this to give every member server a single shared deadline.
+func (svr *GracefulServer) shutdown(ctx context.Context) error {
+	svr.ready.Store(false)
+
+	if svr.PreShutdownDelay > 0 {
+		svr.log.Infof("Waiting %s before shutting down", svr.PreShutdownDelay.String())
+		time.Sleep(svr.PreShutdownDelay)
+	}
+
+	for _, hook := range svr.shutdownHooks {
+		if err := hook(ctx); err != nil {
+			svr.log.WithError(err).Warn("Error running shutdown hook")
+			break
+		}
+	}
+
+	if atomic.LoadInt64(&svr.conns.active) == 0 {
+		svr.log.Info("No active connections, shutting down immediately")
+		return svr.server.Close()
+	}
+
 	svr.log.Infof("Triggering shutdown, in at most %s ", svr.ShutdownTimeout.String())
 	return svr.server.Shutdown(ctx)
 }
@@ -0,0 +1,94 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// Group supervises several GracefulServers - e.g. the public API alongside
+// an internal admin endpoint and a metrics endpoint - under a single
+// shutdown lifecycle. Where a GracefulServer built with WithSignalHandler
+// installs its own signal handler, a Group installs exactly one for all of
+// its members, which serve without their own handlers, and shuts every member down
+// concurrently with a shared deadline when it fires.
+type Group struct {
+	servers []*GracefulServer
+	log     *logrus.Entry
+
+	ShutdownTimeout time.Duration
+}
+
+// NewGroup creates a Group supervising the given servers.
+func NewGroup(log *logrus.Entry, servers ...*GracefulServer) *Group {
+	return &Group{servers: servers, log: log, ShutdownTimeout: DefaultShutdownTimeout}
+}
+
+// Add registers another server with the group. It must be called before
+// Listen.
+func (g *Group) Add(svr *GracefulServer) {
+	g.servers = append(g.servers, svr)
+}
+
+// Listen starts every registered server in its own goroutine and installs
+// one handler for os.Interrupt, SIGTERM and SIGINT; when a signal arrives,
+// Shutdown stops every server concurrently. Listen blocks until every
+// server has stopped serving and returns the first fatal error from
+// serving, if any. A server that fails does not stop the others, and an
+// error from Shutdown is logged, not returned.
+func (g *Group) Listen() error {
+	eg := &errgroup.Group{}
+
+	for _, svr := range g.servers {
+		svr := svr
+		eg.Go(func() error {
+			err := svr.server.Serve(svr.listener)
+			if err != nil && err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		})
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-c
+		g.log.Infof("Triggering shutdown from signal %s", sig)
+		if err := g.Shutdown(); err != nil {
+			g.log.WithError(err).Warn("Error while shutting down group")
+		}
+	}()
+
+	return eg.Wait()
+}
+
+// Shutdown stops every registered server concurrently against a single
+// deadline, set by ShutdownTimeout, and returns the first error
+// encountered.
+func (g *Group) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), g.ShutdownTimeout)
+	defer cancel()
+
+	eg := &errgroup.Group{}
+
+	for _, svr := range g.servers {
+		svr := svr
+		eg.Go(func() error {
+			shutErr := svr.shutdown(ctx)
+			if shutErr == context.DeadlineExceeded {
+				svr.log.WithError(shutErr).Warnf("Forcing a shutdown after waiting %s", g.ShutdownTimeout.String())
+				shutErr = svr.server.Close()
+			}
+			return shutErr
+		})
+	}
+
+	return eg.Wait()
+}
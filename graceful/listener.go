@@ -0,0 +1,46 @@
+package graceful
+
+import (
+	"net"
+	"sync"
+)
+
+// limitListener wraps a net.Listener and blocks Accept once max
+// connections are outstanding, so operators can shed load under pressure
+// instead of exhausting file descriptors. It's the same shape as
+// golang.org/x/net/netutil.LimitListener.
+type limitListener struct {
+	net.Listener
+	sem chan struct{}
+}
+
+func newLimitListener(l net.Listener, max int) net.Listener {
+	if max <= 0 {
+		return l
+	}
+	return &limitListener{Listener: l, sem: make(chan struct{}, max)}
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	l.sem <- struct{}{}
+
+	c, err := l.Listener.Accept()
+	if err != nil {
+		<-l.sem
+		return nil, err
+	}
+
+	return &limitListenerConn{Conn: c, release: func() { <-l.sem }}, nil
+}
+
+type limitListenerConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitListenerConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -0,0 +1,78 @@
+package graceful
+
+import (
+	"context"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Option configures a GracefulServer at construction time.
+type Option func(*GracefulServer)
+
+// WithShutdownTimeout overrides DefaultShutdownTimeout for this server.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(svr *GracefulServer) {
+		svr.ShutdownTimeout = d
+	}
+}
+
+// WithPreShutdownDelay sets how long Close waits, after flipping Readiness
+// to false and before running shutdown hooks, to give load balancers and
+// Kubernetes endpoint controllers time to stop routing traffic here.
+func WithPreShutdownDelay(d time.Duration) Option {
+	return func(svr *GracefulServer) {
+		svr.PreShutdownDelay = d
+	}
+}
+
+// WithReadTimeout sets the underlying http.Server's ReadTimeout.
+func WithReadTimeout(d time.Duration) Option {
+	return func(svr *GracefulServer) {
+		svr.server.ReadTimeout = d
+	}
+}
+
+// WithWriteTimeout sets the underlying http.Server's WriteTimeout.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(svr *GracefulServer) {
+		svr.server.WriteTimeout = d
+	}
+}
+
+// WithMaxHeaderBytes sets the underlying http.Server's MaxHeaderBytes.
+func WithMaxHeaderBytes(n int) Option {
+	return func(svr *GracefulServer) {
+		svr.server.MaxHeaderBytes = n
+	}
+}
+
+// WithBaseContext sets the underlying http.Server's BaseContext.
+func WithBaseContext(fn func(net.Listener) context.Context) Option {
+	return func(svr *GracefulServer) {
+		svr.server.BaseContext = fn
+	}
+}
+
+// WithMaxConcurrentConnections caps how many connections the server will
+// accept at once; further connections wait until one closes. A max of 0,
+// the default, leaves connections unlimited.
+func WithMaxConcurrentConnections(max int) Option {
+	return func(svr *GracefulServer) {
+		svr.MaxConcurrentConnections = max
+	}
+}
+
+// WithSignalHandler opts the server into the legacy behavior of shutting
+// down on an OS signal when run via Listen. If sigs is empty it defaults
+// to os.Interrupt, syscall.SIGTERM and syscall.SIGINT. Servers driven via
+// RunContext don't need this; cancel the context instead.
+func WithSignalHandler(sigs ...os.Signal) Option {
+	return func(svr *GracefulServer) {
+		if len(sigs) == 0 {
+			sigs = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGINT}
+		}
+		svr.signals = sigs
+	}
+}
@@ -0,0 +1,27 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ShutdownHook is run by Close, after Readiness has been flipped to false
+// and PreShutdownDelay has elapsed, but before the underlying server stops
+// accepting requests. Use it to flush queues, close DB pools, or
+// deregister from service discovery.
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown registers fn to run during Close. Hooks run in registration
+// order; the first one to return an error stops the rest, but shutdown
+// proceeds regardless.
+func (svr *GracefulServer) OnShutdown(fn ShutdownHook) {
+	svr.shutdownHooks = append(svr.shutdownHooks, fn)
+}
+
+// Readiness reports whether the server considers itself ready to receive
+// traffic. It's true until Close is called, so a readiness probe backed
+// by this value stops routing traffic as soon as shutdown begins rather
+// than when the server actually stops listening.
+func (svr *GracefulServer) Readiness() *atomic.Bool {
+	return &svr.ready
+}
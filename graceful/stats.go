@@ -0,0 +1,96 @@
+package graceful
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stats is a snapshot of a GracefulServer's connection counters.
+type Stats struct {
+	Active int64
+	Idle   int64
+	Total  int64
+}
+
+// connTracker counts active, idle and total connections via
+// http.Server.ConnState. It tracks each connection's last known state so
+// that a StateClosed transition, which can arrive from either StateActive
+// or StateIdle, decrements the right counter.
+type connTracker struct {
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+
+	active int64
+	idle   int64
+	total  int64
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{states: make(map[net.Conn]http.ConnState)}
+}
+
+func (t *connTracker) trackConnState(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	prev := t.states[conn]
+	if state == http.StateClosed || state == http.StateHijacked {
+		delete(t.states, conn)
+	} else {
+		t.states[conn] = state
+	}
+	t.mu.Unlock()
+
+	switch state {
+	case http.StateNew:
+		atomic.AddInt64(&t.total, 1)
+	case http.StateActive:
+		atomic.AddInt64(&t.active, 1)
+		if prev == http.StateIdle {
+			atomic.AddInt64(&t.idle, -1)
+		}
+	case http.StateIdle:
+		atomic.AddInt64(&t.idle, 1)
+		atomic.AddInt64(&t.active, -1)
+	case http.StateClosed, http.StateHijacked:
+		switch prev {
+		case http.StateActive:
+			atomic.AddInt64(&t.active, -1)
+		case http.StateIdle:
+			atomic.AddInt64(&t.idle, -1)
+		}
+	}
+}
+
+var (
+	connsActiveDesc = prometheus.NewDesc("graceful_server_connections_active", "Number of connections currently handling a request.", nil, nil)
+	connsIdleDesc   = prometheus.NewDesc("graceful_server_connections_idle", "Number of idle keep-alive connections.", nil, nil)
+	connsTotalDesc  = prometheus.NewDesc("graceful_server_connections_total", "Total number of connections accepted.", nil, nil)
+)
+
+// Stats returns a snapshot of the server's current connection counters.
+func (svr *GracefulServer) Stats() Stats {
+	return Stats{
+		Active: atomic.LoadInt64(&svr.conns.active),
+		Idle:   atomic.LoadInt64(&svr.conns.idle),
+		Total:  atomic.LoadInt64(&svr.conns.total),
+	}
+}
+
+// Describe implements prometheus.Collector so a GracefulServer can be
+// registered directly, e.g. prometheus.MustRegister(svr).
+func (svr *GracefulServer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- connsActiveDesc
+	ch <- connsIdleDesc
+	ch <- connsTotalDesc
+}
+
+// Collect implements prometheus.Collector.
+func (svr *GracefulServer) Collect(ch chan<- prometheus.Metric) {
+	stats := svr.Stats()
+	ch <- prometheus.MustNewConstMetric(connsActiveDesc, prometheus.GaugeValue, float64(stats.Active))
+	ch <- prometheus.MustNewConstMetric(connsIdleDesc, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(connsTotalDesc, prometheus.CounterValue, float64(stats.Total))
+}
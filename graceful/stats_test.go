@@ -0,0 +1,53 @@
+package graceful
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// A connection that closes before sending a request transitions
+// StateNew -> StateClosed with no intervening StateActive. Active must
+// stay at 0 through that path, and Close's no-active-connections
+// short-circuit must still fire.
+func TestConnTrackerNewToClosedWithoutRequest(t *testing.T) {
+	svr := NewGracefulServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), logrus.NewEntry(logrus.New()))
+
+	if err := svr.Bind("127.0.0.1:0"); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	go svr.Listen()
+
+	conn, err := net.Dial("tcp", svr.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	conn.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if svr.Stats().Active == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if stats := svr.Stats(); stats.Active != 0 {
+		t.Fatalf("Active = %d, want 0 after a connection closes before sending a request", stats.Active)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- svr.Close() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not short-circuit with no active connections")
+	}
+}
@@ -0,0 +1,129 @@
+package graceful
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+)
+
+// certReloader keeps the currently active certificate behind an atomic
+// pointer so it can be swapped out without racing with in-flight
+// handshakes, and knows how to re-read it from disk on demand.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	current  atomic.Value // *tls.Certificate
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.current.Store(&cert)
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current.Load().(*tls.Certificate), nil
+}
+
+// BindTLS binds addr and configures the server to terminate TLS itself
+// using the given certificate and key, with HTTP/2 negotiated via ALPN.
+// Sending SIGHUP to the process reloads the certificate and key from disk
+// without dropping existing connections.
+func (svr *GracefulServer) BindTLS(addr, certFile, keyFile string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		l.Close()
+		return err
+	}
+
+	svr.server.TLSConfig = &tls.Config{
+		GetCertificate: reloader.getCertificate,
+	}
+	if err := http2.ConfigureServer(svr.server, &http2.Server{}); err != nil {
+		l.Close()
+		return err
+	}
+
+	svr.certReloader = reloader
+	svr.URL = "https://" + l.Addr().String()
+	svr.listener = tls.NewListener(newLimitListener(l, svr.MaxConcurrentConnections), svr.server.TLSConfig)
+
+	go svr.watchForCertReload()
+
+	return nil
+}
+
+// BindAutocert binds addr and configures the server to terminate TLS using
+// certificates managed by m, e.g. via ACME/Let's Encrypt, instead of a
+// certificate and key on disk.
+func (svr *GracefulServer) BindAutocert(addr string, m *autocert.Manager) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	svr.server.TLSConfig = m.TLSConfig()
+	if err := http2.ConfigureServer(svr.server, &http2.Server{}); err != nil {
+		l.Close()
+		return err
+	}
+
+	svr.URL = "https://" + l.Addr().String()
+	svr.listener = tls.NewListener(newLimitListener(l, svr.MaxConcurrentConnections), svr.server.TLSConfig)
+
+	return nil
+}
+
+// ListenAndServeTLS binds addr with BindTLS and then serves, equivalent to
+// calling ListenAndServe for a plain-HTTP server.
+func (svr *GracefulServer) ListenAndServeTLS(addr, certFile, keyFile string) error {
+	if svr.listener != nil {
+		return errors.New("The listener has already started, don't call Bind first")
+	}
+	if err := svr.BindTLS(addr, certFile, keyFile); err != nil {
+		return err
+	}
+
+	return svr.Listen()
+}
+
+// watchForCertReload reloads the certificate and key from disk whenever the
+// process receives SIGHUP. It is a no-op for servers that weren't bound
+// with BindTLS.
+func (svr *GracefulServer) watchForCertReload() {
+	if svr.certReloader == nil {
+		return
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	for range c {
+		svr.log.Info("Reloading TLS certificate")
+		if err := svr.certReloader.reload(); err != nil {
+			svr.log.WithError(err).Warn("Failed to reload TLS certificate")
+		}
+	}
+}